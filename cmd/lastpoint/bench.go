@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	"github.com/golovatskygroup/lastpoint/internal/bench"
+)
+
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	var (
+		http1URL    = fs.String("http1-url", "http://localhost:9081/", "HTTP/1.1 target URL")
+		http2URL    = fs.String("http2-url", "https://localhost:9443/", "HTTP/2 target URL")
+		concurrency = fs.Int("concurrency", 50, "concurrent workers per target")
+		requests    = fs.Int("requests", 10000, "total requests per target (ignored if -duration is set)")
+		duration    = fs.Duration("duration", 0, "run each target for this long instead of a fixed request count")
+		insecure    = fs.Bool("insecure-skip-verify", true, "skip TLS verification against the demo server's self-signed cert")
+		jsonOutput  = fs.Bool("json", false, "emit results as JSON instead of a table")
+	)
+	fs.Parse(args)
+
+	ctx := context.Background()
+
+	targets := []bench.Config{
+		{
+			Name:               "http/1.1",
+			URL:                *http1URL,
+			Concurrency:        *concurrency,
+			Requests:           *requests,
+			Duration:           *duration,
+			InsecureSkipVerify: *insecure,
+		},
+		{
+			Name:               "http/2",
+			URL:                *http2URL,
+			Concurrency:        *concurrency,
+			Requests:           *requests,
+			Duration:           *duration,
+			ForceHTTP2:         true,
+			InsecureSkipVerify: *insecure,
+		},
+	}
+
+	results := make([]bench.Result, 0, len(targets))
+	for _, target := range targets {
+		result, err := bench.Run(ctx, target)
+		if err != nil {
+			fatalf("bench: %s: %v", target.Name, err)
+		}
+		results = append(results, result)
+	}
+
+	if *jsonOutput {
+		if err := bench.WriteJSON(os.Stdout, results); err != nil {
+			fatalf("bench: %v", err)
+		}
+		return
+	}
+	bench.WriteTable(os.Stdout, results)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// envOr returns the value of the environment variable key, or fallback if
+// it's unset. Flags still win: callers pass the result as a flag's default,
+// so an explicit -flag on the command line overrides it.
+func envOr(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return fallback
+}
+
+func envBoolOr(key string, fallback bool) bool {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fallback
+	}
+	return b
+}
+
+func envDurationOr(key string, fallback time.Duration) time.Duration {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fallback
+	}
+	return d
+}
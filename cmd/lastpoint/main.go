@@ -0,0 +1,31 @@
+// Command lastpoint runs the HTTP/1.1 and HTTP/2 demo servers this module
+// uses to compare the two protocols, from one process, and can also drive
+// load against them via the "bench" subcommand.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	args := os.Args[1:]
+	if len(args) == 0 {
+		runServe(args)
+		return
+	}
+
+	switch args[0] {
+	case "bench":
+		runBench(args[1:])
+	case "serve":
+		runServe(args[1:])
+	default:
+		runServe(args)
+	}
+}
+
+func fatalf(format string, a ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", a...)
+	os.Exit(1)
+}
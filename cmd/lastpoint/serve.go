@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/golovatskygroup/lastpoint/examples/greeter"
+	"github.com/golovatskygroup/lastpoint/internal/grpcmux"
+	"github.com/golovatskygroup/lastpoint/internal/ratelimit"
+	"github.com/golovatskygroup/lastpoint/internal/server"
+	"github.com/golovatskygroup/lastpoint/internal/tlsutil"
+)
+
+// stringList collects repeated occurrences of a flag, e.g. -host a -host b.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// Environment variables read as fallback defaults for the flags below; an
+// explicit flag on the command line always wins.
+const (
+	envHTTPAddr     = "LASTPOINT_HTTP_ADDR"
+	envHTTPSAddr    = "LASTPOINT_HTTPS_ADDR"
+	envCertFile     = "LASTPOINT_CERT"
+	envKeyFile      = "LASTPOINT_KEY"
+	envH2C          = "LASTPOINT_H2C"
+	envDrainTimeout = "LASTPOINT_DRAIN_TIMEOUT"
+)
+
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	var hosts stringList
+	var (
+		httpAddr     = fs.String("http-addr", envOr(envHTTPAddr, ":9081"), "address for the HTTP/1.1 (and h2c, if enabled) listener; empty disables it (env "+envHTTPAddr+")")
+		httpsAddr    = fs.String("https-addr", envOr(envHTTPSAddr, ":9443"), "address for the TLS HTTP/2 listener; empty disables it (env "+envHTTPSAddr+")")
+		certFile     = fs.String("cert", envOr(envCertFile, "cert.pem"), "TLS certificate path for the HTTPS listener (env "+envCertFile+")")
+		keyFile      = fs.String("key", envOr(envKeyFile, "key.pem"), "TLS key path for the HTTPS listener (env "+envKeyFile+")")
+		enableH2C    = fs.Bool("h2c", envBoolOr(envH2C, false), "serve cleartext HTTP/2 (h2c) on -http-addr (env "+envH2C+")")
+		enableGRPC   = fs.Bool("grpc", false, "mount the example gRPC Greeter service on the HTTPS listener")
+		tlsMode      = fs.String("tls", "file", "how to obtain the HTTPS certificate: file, self-signed, or acme")
+		domain       = fs.String("domain", "", "domain to request a certificate for in -tls=acme mode")
+		acmeCache    = fs.String("acme-cache", "acme-cache", "directory autocert uses to cache ACME account/certificate data")
+		maxInflight  = fs.Int("max-inflight", 0, "global cap on simultaneous in-flight requests; 0 disables it")
+		rpsPerIP     = fs.Float64("rps-per-ip", 0, "per-remote-IP request rate limit; 0 disables it")
+		burstPerIP   = fs.Int("burst-per-ip", 1, "per-remote-IP token bucket burst size")
+		drainTimeout = fs.Duration("drain-timeout", envDurationOr(envDrainTimeout, 15*time.Second), "how long to wait for in-flight requests during shutdown (env "+envDrainTimeout+")")
+	)
+	fs.Var(&hosts, "host", "additional SAN for -tls=self-signed (repeatable)")
+	fs.Parse(args)
+
+	runtime.GOMAXPROCS(runtime.NumCPU())
+
+	cfg := server.Config{
+		HTTPAddr:     *httpAddr,
+		HTTPSAddr:    *httpsAddr,
+		CertFile:     *certFile,
+		KeyFile:      *keyFile,
+		EnableH2C:    *enableH2C,
+		DrainTimeout: *drainTimeout,
+		Handler:      server.DefaultMux(),
+	}
+
+	if *enableGRPC {
+		grpcServer := grpc.NewServer()
+		greeter.RegisterGreeterServer(grpcServer, &greeter.Server{})
+		cfg.HTTPSHandler = grpcmux.NewMuxedServer(server.DefaultMux(), grpcServer).Handler
+	}
+
+	if *maxInflight > 0 || *rpsPerIP > 0 {
+		limiter := ratelimit.New(*maxInflight, *rpsPerIP, *burstPerIP)
+		cfg.Handler = limiter.Middleware(cfg.Handler)
+		if cfg.HTTPSHandler != nil {
+			cfg.HTTPSHandler = limiter.Middleware(cfg.HTTPSHandler)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if cfg.HTTPSAddr != "" {
+		switch *tlsMode {
+		case "file":
+			// Use -cert/-key as-is; srv.Run will fail at listen time if
+			// they don't exist.
+		case "self-signed":
+			if err := tlsutil.EnsureSelfSigned(*certFile, *keyFile, hosts); err != nil {
+				log.Fatal(err)
+			}
+		case "acme":
+			if *domain == "" {
+				log.Fatal("lastpoint: -tls=acme requires -domain")
+			}
+			manager := tlsutil.NewACMEManager(*acmeCache, *domain)
+			go tlsutil.ServeHTTPChallenge(ctx, manager)
+			cfg.TLSConfig = tlsutil.TLSConfig(manager)
+		default:
+			log.Fatalf("lastpoint: unknown -tls mode %q", *tlsMode)
+		}
+	}
+
+	srv, err := server.New(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := srv.Run(ctx); err != nil {
+		log.Fatal(err)
+	}
+}
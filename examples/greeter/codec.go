@@ -0,0 +1,25 @@
+package greeter
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// jsonCodecName is the gRPC content-subtype this package's messages are
+// marshaled with (negotiated via Content-Type: application/grpc+json),
+// since HelloRequest/HelloReply are plain structs rather than generated
+// protobuf types.
+const jsonCodecName = "json"
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+func (jsonCodec) Name() string { return jsonCodecName }
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
@@ -0,0 +1,35 @@
+// Package greeter is a small hand-maintained gRPC service used to
+// demonstrate mounting a gRPC server on the same HTTP/2 listener as the
+// plain JSON demo handler (see internal/grpcmux).
+//
+// The repo has no protoc/protoc-gen-go toolchain available to generate real
+// wire types for greeter.proto, so HelloRequest/HelloReply are plain structs
+// marshaled with encoding/json via the codec in codec.go rather than
+// protobuf — see greeter.proto for the service contract this mirrors.
+package greeter
+
+// HelloRequest is the SayHello request message.
+type HelloRequest struct {
+	Name string `json:"name"`
+}
+
+// GetName returns the requested name, or "" if req is nil.
+func (req *HelloRequest) GetName() string {
+	if req == nil {
+		return ""
+	}
+	return req.Name
+}
+
+// HelloReply is the SayHello response message.
+type HelloReply struct {
+	Message string `json:"message"`
+}
+
+// GetMessage returns the greeting, or "" if reply is nil.
+func (reply *HelloReply) GetMessage() string {
+	if reply == nil {
+		return ""
+	}
+	return reply.Message
+}
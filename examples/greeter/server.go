@@ -0,0 +1,20 @@
+package greeter
+
+import (
+	"context"
+	"fmt"
+)
+
+// Server is a minimal GreeterServer implementation.
+type Server struct {
+	UnimplementedGreeterServer
+}
+
+// SayHello greets the caller by name.
+func (s *Server) SayHello(ctx context.Context, req *HelloRequest) (*HelloReply, error) {
+	name := req.GetName()
+	if name == "" {
+		name = "World"
+	}
+	return &HelloReply{Message: fmt.Sprintf("Hello, %s!", name)}, nil
+}
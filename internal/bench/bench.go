@@ -0,0 +1,195 @@
+// Package bench drives concurrent load against the lastpoint demo servers
+// and reports latency, throughput, and connection-reuse statistics so the
+// HTTP/1.1 and HTTP/2 listeners can be compared on more than vibes.
+package bench
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+// Config describes one target to load-test.
+type Config struct {
+	// Name labels the target in reports, e.g. "http/1.1" or "http/2".
+	Name string
+	// URL is the endpoint to hit repeatedly.
+	URL string
+	// Concurrency is the number of workers issuing requests in parallel.
+	Concurrency int
+	// Requests is the total number of requests to issue across all workers.
+	Requests int
+	// Duration, if non-zero, runs the target for this long instead of a
+	// fixed request count.
+	Duration time.Duration
+	// ForceHTTP2 uses golang.org/x/net/http2.Transport directly instead of
+	// net/http's transport negotiation.
+	ForceHTTP2 bool
+	// InsecureSkipVerify allows hitting the demo server's self-signed cert.
+	InsecureSkipVerify bool
+}
+
+// Result summarizes one Config's run.
+type Result struct {
+	Name          string        `json:"name"`
+	Requests      int           `json:"requests"`
+	Errors        int           `json:"errors"`
+	BytesRead     int64         `json:"bytes_read"`
+	Elapsed       time.Duration `json:"elapsed"`
+	ThroughputRPS float64       `json:"throughput_rps"`
+	ConnsReused   int64         `json:"conns_reused"`
+	P50           time.Duration `json:"p50"`
+	P90           time.Duration `json:"p90"`
+	P99           time.Duration `json:"p99"`
+}
+
+// Run executes cfg and returns the resulting Result.
+func Run(ctx context.Context, cfg Config) (Result, error) {
+	client := &http.Client{Transport: newTransport(cfg)}
+
+	var (
+		mu        sync.Mutex
+		latencies []time.Duration
+		bytesRead int64
+		errs      int64
+		reused    int64
+	)
+
+	var deadline time.Time
+	if cfg.Duration > 0 {
+		deadline = time.Now().Add(cfg.Duration)
+	}
+
+	var requestsIssued int64
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if ctx.Err() != nil {
+					return
+				}
+				if cfg.Duration > 0 {
+					if time.Now().After(deadline) {
+						return
+					}
+				} else if atomic.AddInt64(&requestsIssued, 1) > int64(cfg.Requests) {
+					return
+				}
+
+				reqStart := time.Now()
+				n, err := doRequest(ctx, client, cfg.URL, &reused)
+				lat := time.Since(reqStart)
+
+				mu.Lock()
+				if err != nil {
+					errs++
+				} else {
+					latencies = append(latencies, lat)
+					bytesRead += n
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	result := Result{
+		Name:        cfg.Name,
+		Requests:    len(latencies),
+		Errors:      int(errs),
+		BytesRead:   bytesRead,
+		Elapsed:     elapsed,
+		ConnsReused: atomic.LoadInt64(&reused),
+		P50:         percentile(latencies, 0.50),
+		P90:         percentile(latencies, 0.90),
+		P99:         percentile(latencies, 0.99),
+	}
+	if elapsed > 0 {
+		result.ThroughputRPS = float64(result.Requests) / elapsed.Seconds()
+	}
+	return result, nil
+}
+
+// doRequest issues a single GET, tracking via httptrace whether the
+// connection it lands on was reused from the pool.
+func doRequest(ctx context.Context, client *http.Client, url string, reused *int64) (int64, error) {
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				atomic.AddInt64(reused, 1)
+			}
+		},
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return io.Copy(io.Discard, resp.Body)
+}
+
+// newTransport builds the client transport for cfg: either net/http's own
+// transport (with ForceAttemptHTTP2 set, so https URLs negotiate HTTP/2 via
+// ALPN and http URLs use HTTP/1.1) or, when cfg.ForceHTTP2 is set, an
+// explicit golang.org/x/net/http2.Transport, which speaks HTTP/2 over TLS
+// only and rejects plaintext http URLs.
+func newTransport(cfg Config) http.RoundTripper {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+
+	if cfg.ForceHTTP2 {
+		return &http2.Transport{TLSClientConfig: tlsConfig}
+	}
+	return &http.Transport{
+		TLSClientConfig:   tlsConfig,
+		ForceAttemptHTTP2: true,
+	}
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p * float64(len(sorted)))
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// WriteTable renders results as a human-readable table.
+func WriteTable(w io.Writer, results []Result) {
+	fmt.Fprintf(w, "%-10s %8s %8s %10s %10s %10s %10s %10s %8s\n",
+		"target", "reqs", "errs", "p50", "p90", "p99", "rps", "bytes", "reused")
+	for _, r := range results {
+		fmt.Fprintf(w, "%-10s %8d %8d %10s %10s %10s %10.1f %10d %8d\n",
+			r.Name, r.Requests, r.Errors, r.P50, r.P90, r.P99, r.ThroughputRPS, r.BytesRead, r.ConnsReused)
+	}
+}
+
+// WriteJSON renders results as JSON for scripting.
+func WriteJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
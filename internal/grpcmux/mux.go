@@ -0,0 +1,33 @@
+// Package grpcmux lets a single HTTP/2 listener serve both a regular
+// net/http handler and a gRPC server, dispatching on content type.
+package grpcmux
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+// NewMuxedServer returns an *http.Server that routes HTTP/2 gRPC requests
+// (Content-Type: application/grpc*) to grpcServer and everything else to
+// httpHandler, so both can share one TLS listener and port.
+func NewMuxedServer(httpHandler http.Handler, grpcServer *grpc.Server) *http.Server {
+	return &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isGRPCRequest(r) {
+				grpcServer.ServeHTTP(w, r)
+				return
+			}
+			httpHandler.ServeHTTP(w, r)
+		}),
+		TLSConfig: &tls.Config{
+			NextProtos: []string{"h2"},
+		},
+	}
+}
+
+func isGRPCRequest(r *http.Request) bool {
+	return r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc")
+}
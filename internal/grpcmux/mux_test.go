@@ -0,0 +1,72 @@
+package grpcmux
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/golovatskygroup/lastpoint/examples/greeter"
+)
+
+type statusResponse struct {
+	Status string `json:"status"`
+}
+
+func jsonHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(statusResponse{Status: "ok"})
+	})
+}
+
+func TestMuxedServerServesJSONAndGRPC(t *testing.T) {
+	grpcServer := grpc.NewServer()
+	greeter.RegisterGreeterServer(grpcServer, &greeter.Server{})
+
+	muxed := NewMuxedServer(jsonHandler(), grpcServer)
+
+	ts := httptest.NewUnstartedServer(muxed.Handler)
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	t.Run("json endpoint", func(t *testing.T) {
+		client := ts.Client()
+		resp, err := client.Get(ts.URL + "/status")
+		if err != nil {
+			t.Fatalf("GET /status: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var body statusResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if body.Status != "ok" {
+			t.Fatalf("got status %q, want ok", body.Status)
+		}
+	})
+
+	t.Run("grpc method", func(t *testing.T) {
+		creds := credentials.NewTLS(ts.Client().Transport.(*http.Transport).TLSClientConfig)
+		conn, err := grpc.NewClient(ts.Listener.Addr().String(), grpc.WithTransportCredentials(creds))
+		if err != nil {
+			t.Fatalf("grpc.NewClient: %v", err)
+		}
+		defer conn.Close()
+
+		client := greeter.NewGreeterClient(conn)
+		reply, err := client.SayHello(context.Background(), &greeter.HelloRequest{Name: "lastpoint"})
+		if err != nil {
+			t.Fatalf("SayHello: %v", err)
+		}
+		if want := "Hello, lastpoint!"; reply.GetMessage() != want {
+			t.Fatalf("got message %q, want %q", reply.GetMessage(), want)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"container/list"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// lruBuckets holds one rate.Limiter per key, evicting the least-recently-used
+// entry once the map grows past capacity so a flood of distinct IPs can't
+// grow it unbounded.
+type lruBuckets struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type bucketEntry struct {
+	key     string
+	limiter *rate.Limiter
+}
+
+func newLRUBuckets(capacity int) *lruBuckets {
+	return &lruBuckets{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// get returns the limiter for key, creating one with the given rps/burst if
+// it doesn't exist yet, and marks it most-recently-used.
+func (b *lruBuckets) get(key string, rps rate.Limit, burst int) *rate.Limiter {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elem, ok := b.entries[key]; ok {
+		b.order.MoveToFront(elem)
+		return elem.Value.(*bucketEntry).limiter
+	}
+
+	limiter := rate.NewLimiter(rps, burst)
+	elem := b.order.PushFront(&bucketEntry{key: key, limiter: limiter})
+	b.entries[key] = elem
+
+	if b.order.Len() > b.capacity {
+		oldest := b.order.Back()
+		if oldest != nil {
+			b.order.Remove(oldest)
+			delete(b.entries, oldest.Value.(*bucketEntry).key)
+		}
+	}
+
+	return limiter
+}
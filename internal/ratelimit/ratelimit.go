@@ -0,0 +1,71 @@
+// Package ratelimit provides HTTP middleware that caps simultaneous
+// in-flight requests and enforces a per-client-IP rate limit, so the demo
+// servers can be driven by the bench subcommand (or anything else) without
+// accidentally taking down the host.
+package ratelimit
+
+import (
+	"net"
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// Limiter is HTTP middleware enforcing a global cap on in-flight requests
+// and a per-remote-IP token-bucket rate limit.
+type Limiter struct {
+	inflight chan struct{}
+	buckets  *lruBuckets
+	rps      rate.Limit
+	burst    int
+}
+
+// New builds a Limiter. maxInflight <= 0 disables the global cap; rps <= 0
+// disables the per-IP limit.
+func New(maxInflight int, rps float64, burst int) *Limiter {
+	l := &Limiter{
+		rps:   rate.Limit(rps),
+		burst: burst,
+	}
+	if maxInflight > 0 {
+		l.inflight = make(chan struct{}, maxInflight)
+	}
+	if rps > 0 {
+		l.buckets = newLRUBuckets(10000)
+	}
+	return l
+}
+
+// Middleware wraps next with the in-flight cap and per-IP rate limit.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l.buckets != nil {
+			if !l.allow(r.RemoteAddr) {
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		if l.inflight != nil {
+			select {
+			case l.inflight <- struct{}{}:
+				defer func() { <-l.inflight }()
+			default:
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "server too busy", http.StatusServiceUnavailable)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *Limiter) allow(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return l.buckets.get(host, l.rps, l.burst).Allow()
+}
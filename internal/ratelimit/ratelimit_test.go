@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestMiddlewareEnforcesMaxInflight(t *testing.T) {
+	release := make(chan struct{})
+	blocking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	l := New(1, 0, 0)
+	handler := l.Middleware(blocking)
+
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		done <- rec
+	}()
+
+	// Give the first request time to acquire the inflight slot.
+	for len(l.inflight) == 0 {
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("got status %d, want 503 while at max inflight", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header on 503")
+	}
+
+	close(release)
+	first := <-done
+	if first.Code != http.StatusOK {
+		t.Fatalf("got status %d for first request, want 200", first.Code)
+	}
+}
+
+func TestMiddlewareEnforcesPerIPRateLimit(t *testing.T) {
+	l := New(0, 1, 1)
+	handler := l.Middleware(okHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "203.0.113.5:1234"
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, req)
+	if first.Code != http.StatusOK {
+		t.Fatalf("first request got %d, want 200", first.Code)
+	}
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, req)
+	if second.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request got %d, want 429", second.Code)
+	}
+
+	other := httptest.NewRequest(http.MethodGet, "/", nil)
+	other.RemoteAddr = "198.51.100.7:1234"
+	otherRec := httptest.NewRecorder()
+	handler.ServeHTTP(otherRec, other)
+	if otherRec.Code != http.StatusOK {
+		t.Fatalf("different IP got %d, want 200", otherRec.Code)
+	}
+}
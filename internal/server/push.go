@@ -0,0 +1,89 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+)
+
+// PushIfSupported opportunistically pushes targets to the client using
+// http.Pusher, forwarding the incoming request's Accept-Encoding so pushed
+// responses can be compressed the same way a client-initiated request for
+// them would be. It is a no-op (returning false) when the connection isn't
+// HTTP/2 or the client disabled push, so callers can use it unconditionally.
+func PushIfSupported(w http.ResponseWriter, r *http.Request, targets ...string) bool {
+	pusher, ok := w.(http.Pusher)
+	if !ok {
+		return false
+	}
+	var header http.Header
+	if ae := r.Header.Get("Accept-Encoding"); ae != "" {
+		header = http.Header{"Accept-Encoding": []string{ae}}
+	}
+	for _, target := range targets {
+		if err := pusher.Push(target, &http.PushOptions{
+			Method: http.MethodGet,
+			Header: header,
+		}); err != nil {
+			if errors.Is(err, http.ErrNotSupported) {
+				// The client sent SETTINGS_ENABLE_PUSH=0.
+				return false
+			}
+			log.Printf("lastpoint: push %s: %v", target, err)
+		}
+	}
+	return true
+}
+
+const demoIndexHTML = `<!doctype html>
+<html>
+<head>
+<link rel="stylesheet" href="/static/style.css">
+<script src="/static/app.js" defer></script>
+</head>
+<body>
+<h1>Hello, World!</h1>
+<img src="/static/logo.png" alt="logo">
+</body>
+</html>`
+
+// DemoIndexHandler serves an HTML page over HTTP/2 and pushes the CSS, JS,
+// and image it references, demonstrating a feature HTTP/1.1 has no
+// equivalent for.
+func DemoIndexHandler(w http.ResponseWriter, r *http.Request) {
+	PushIfSupported(w, r, "/static/style.css", "/static/app.js", "/static/logo.png")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(demoIndexHTML))
+}
+
+var demoStaticAssets = map[string]struct {
+	contentType string
+	body        string
+}{
+	"/static/style.css": {"text/css", "body { font-family: sans-serif; }"},
+	"/static/app.js":    {"application/javascript", "console.log('pushed');"},
+	"/static/logo.png":  {"image/png", ""},
+}
+
+// DemoStaticHandler serves the small set of assets referenced by
+// DemoIndexHandler so a pushed stream has somewhere real to resolve to.
+func DemoStaticHandler(w http.ResponseWriter, r *http.Request) {
+	asset, ok := demoStaticAssets[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", asset.contentType)
+	w.Write([]byte(asset.body))
+}
+
+// DefaultMux returns the handler lastpoint serves by default: the plain
+// Hello-World handler at "/", plus the push demo and its static assets.
+func DefaultMux() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", Handler)
+	mux.HandleFunc("/push-demo", DemoIndexHandler)
+	mux.HandleFunc("/static/", DemoStaticHandler)
+	return mux
+}
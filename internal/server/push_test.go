@@ -0,0 +1,239 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+func TestDemoIndexHandlerServesIndexAndAssetsOverHTTP2(t *testing.T) {
+	ts := httptest.NewUnstartedServer(DefaultMux())
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	client := ts.Client()
+	client.Transport = &http2.Transport{
+		TLSClientConfig: ts.Client().Transport.(*http.Transport).TLSClientConfig,
+	}
+
+	resp, err := client.Get(ts.URL + "/push-demo")
+	if err != nil {
+		t.Fatalf("GET /push-demo: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.ProtoMajor != 2 {
+		t.Fatalf("expected HTTP/2, got proto major %d", resp.ProtoMajor)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	if len(body) == 0 {
+		t.Fatal("expected non-empty index body")
+	}
+
+	// golang.org/x/net/http2.Transport always advertises
+	// SETTINGS_ENABLE_PUSH=0, so PushIfSupported's pushes are declined at the
+	// protocol level and each asset must still resolve on its own request.
+	// See TestDemoIndexHandlerSendsPushPromiseFrames for a client that
+	// actually enables push and observes the promised streams.
+	for _, target := range []string{"/static/style.css", "/static/app.js", "/static/logo.png"} {
+		assetResp, err := client.Get(ts.URL + target)
+		if err != nil {
+			t.Fatalf("GET %s: %v", target, err)
+		}
+		assetResp.Body.Close()
+		if assetResp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s: got status %d, want 200", target, assetResp.StatusCode)
+		}
+	}
+}
+
+func TestPushIfSupportedNoOpWithoutPusher(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if PushIfSupported(rec, req, "/static/style.css") {
+		t.Fatal("expected PushIfSupported to report false for a non-Pusher ResponseWriter")
+	}
+}
+
+// fakePusher is an http.ResponseWriter that also implements http.Pusher, so
+// PushIfSupported's real push path can be exercised without a live HTTP/2
+// connection.
+type fakePusher struct {
+	http.ResponseWriter
+	pushed []*http.PushOptions
+}
+
+func (f *fakePusher) Push(target string, opts *http.PushOptions) error {
+	f.pushed = append(f.pushed, opts)
+	return nil
+}
+
+func TestPushIfSupportedForwardsRequestAcceptEncoding(t *testing.T) {
+	w := &fakePusher{ResponseWriter: httptest.NewRecorder()}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip, br")
+
+	if !PushIfSupported(w, req, "/static/style.css") {
+		t.Fatal("expected PushIfSupported to report true for a Pusher ResponseWriter")
+	}
+	if len(w.pushed) != 1 {
+		t.Fatalf("got %d pushes, want 1", len(w.pushed))
+	}
+	if got := w.pushed[0].Header.Get("Accept-Encoding"); got != "gzip, br" {
+		t.Errorf("pushed Accept-Encoding = %q, want %q (from the request, not the response)", got, "gzip, br")
+	}
+}
+
+// TestDemoIndexHandlerSendsPushPromiseFrames drives the HTTP/2 wire protocol
+// directly (rather than through golang.org/x/net/http2.Transport, which
+// always disables push) so it can assert on the actual PUSH_PROMISE frames
+// DemoIndexHandler's PushIfSupported call produces.
+func TestDemoIndexHandlerSendsPushPromiseFrames(t *testing.T) {
+	ts := httptest.NewUnstartedServer(DefaultMux())
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	tlsConfig := ts.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+	tlsConfig.NextProtos = []string{http2.NextProtoTLS}
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("parse server URL: %v", err)
+	}
+
+	conn, err := tls.Dial("tcp", u.Host, tlsConfig)
+	if err != nil {
+		t.Fatalf("tls.Dial: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if _, err := conn.Write([]byte(http2.ClientPreface)); err != nil {
+		t.Fatalf("write client preface: %v", err)
+	}
+
+	framer := http2.NewFramer(conn, conn)
+	// Explicitly enable push and advertise room for a few concurrent
+	// streams; the default client settings (what Transport sends) disable
+	// push, which is exactly the behavior this test needs to get around.
+	if err := framer.WriteSettings(
+		http2.Setting{ID: http2.SettingEnablePush, Val: 1},
+		http2.Setting{ID: http2.SettingMaxConcurrentStreams, Val: 10},
+	); err != nil {
+		t.Fatalf("write client settings: %v", err)
+	}
+
+	if err := ackServerSettings(framer); err != nil {
+		t.Fatalf("settings handshake: %v", err)
+	}
+
+	var headerBlock strings.Builder
+	enc := hpack.NewEncoder(&headerBlock)
+	for _, f := range []hpack.HeaderField{
+		{Name: ":method", Value: "GET"},
+		{Name: ":scheme", Value: "https"},
+		{Name: ":authority", Value: u.Host},
+		{Name: ":path", Value: "/push-demo"},
+	} {
+		if err := enc.WriteField(f); err != nil {
+			t.Fatalf("encode header %s: %v", f.Name, err)
+		}
+	}
+
+	if err := framer.WriteHeaders(http2.HeadersFrameParam{
+		StreamID:      1,
+		BlockFragment: []byte(headerBlock.String()),
+		EndStream:     true,
+		EndHeaders:    true,
+	}); err != nil {
+		t.Fatalf("write headers: %v", err)
+	}
+
+	pushedPaths, err := readPushPromisePaths(framer, 3)
+	if err != nil {
+		t.Fatalf("read push promises: %v", err)
+	}
+
+	want := []string{"/static/style.css", "/static/app.js", "/static/logo.png"}
+	for _, path := range want {
+		if !pushedPaths[path] {
+			t.Errorf("expected a PUSH_PROMISE for %s, got %v", path, pushedPaths)
+		}
+	}
+}
+
+// ackServerSettings reads frames until it has seen and acked the server's
+// initial (non-ack) SETTINGS frame.
+func ackServerSettings(framer *http2.Framer) error {
+	for {
+		frame, err := framer.ReadFrame()
+		if err != nil {
+			return err
+		}
+		settings, ok := frame.(*http2.SettingsFrame)
+		if !ok || settings.IsAck() {
+			continue
+		}
+		return framer.WriteSettingsAck()
+	}
+}
+
+// readPushPromisePaths reads frames until it has seen want PUSH_PROMISE
+// frames, returning the set of :path pseudo-headers the server promised.
+// The server's HPACK encoder's dynamic table is shared across every header
+// block it writes on the connection (the response HEADERS included), so
+// every header block must be decoded in arrival order to keep the decoder's
+// table in sync even though only PUSH_PROMISE blocks are of interest here.
+func readPushPromisePaths(framer *http2.Framer, want int) (map[string]bool, error) {
+	paths := make(map[string]bool)
+	dec := hpack.NewDecoder(4096, nil)
+
+	for len(paths) < want {
+		frame, err := framer.ReadFrame()
+		if err != nil {
+			return paths, fmt.Errorf("after %d push promises: %w", len(paths), err)
+		}
+
+		var (
+			headerBlock []byte
+			isPush      bool
+		)
+		switch f := frame.(type) {
+		case *http2.PushPromiseFrame:
+			headerBlock, isPush = f.HeaderBlockFragment(), true
+		case *http2.HeadersFrame:
+			headerBlock = f.HeaderBlockFragment()
+		default:
+			continue
+		}
+
+		fields, err := dec.DecodeFull(headerBlock)
+		if err != nil {
+			return paths, fmt.Errorf("decode header block: %w", err)
+		}
+		if !isPush {
+			continue
+		}
+		for _, f := range fields {
+			if f.Name == ":path" {
+				paths[f.Value] = true
+			}
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,171 @@
+// Package server wires up the HTTP/1.1 and HTTP/2 listeners that lastpoint
+// uses to demonstrate the two protocols side by side.
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// Config controls which listeners Group starts and how they behave.
+type Config struct {
+	// HTTPAddr is the address for the plaintext HTTP/1.1 (and optional h2c)
+	// listener, e.g. ":9081". Leave empty to disable it.
+	HTTPAddr string
+	// HTTPSAddr is the address for the TLS HTTP/2 listener, e.g. ":9443".
+	// Leave empty to disable it.
+	HTTPSAddr string
+	// CertFile and KeyFile are the PEM paths used by the HTTPS listener.
+	CertFile string
+	KeyFile  string
+	// EnableH2C allows HTTP/2 without TLS on HTTPAddr via golang.org/x/net/http2/h2c.
+	EnableH2C bool
+	// DrainTimeout bounds how long Shutdown waits for in-flight requests to
+	// finish before Run returns.
+	DrainTimeout time.Duration
+
+	// Handler serves both listeners. Defaults to Handler if nil.
+	Handler http.Handler
+
+	// HTTPSHandler, if set, overrides Handler for the HTTPS listener only.
+	// This is how a caller mounts a grpcmux-wrapped handler (see
+	// internal/grpcmux) on the HTTP/2 port while the plaintext port keeps
+	// serving Handler.
+	HTTPSHandler http.Handler
+
+	// TLSConfig, if set, replaces the default NextProtos-only tls.Config for
+	// the HTTPS listener. This is how ACME mode (see internal/tlsutil) wires
+	// in GetCertificate instead of CertFile/KeyFile.
+	TLSConfig *tls.Config
+}
+
+// Handler is the default demo handler shared by the HTTP/1.1 and HTTP/2
+// listeners so the two protocols can be compared against identical output.
+func Handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprint(w, "Hello, World!")
+}
+
+// Group owns the listeners started for a Config and shuts them all down
+// together.
+type Group struct {
+	cfg     Config
+	servers []*http.Server
+}
+
+// New builds the *http.Server instances described by cfg but does not start
+// them. It returns an error if neither listener is enabled.
+func New(cfg Config) (*Group, error) {
+	if cfg.HTTPAddr == "" && cfg.HTTPSAddr == "" {
+		return nil, errors.New("server: no listeners configured")
+	}
+	if cfg.Handler == nil {
+		cfg.Handler = http.HandlerFunc(Handler)
+	}
+	if cfg.DrainTimeout <= 0 {
+		cfg.DrainTimeout = 15 * time.Second
+	}
+
+	g := &Group{cfg: cfg}
+
+	if cfg.HTTPAddr != "" {
+		h := cfg.Handler
+		if cfg.EnableH2C {
+			h2s := &http2.Server{}
+			h = h2c.NewHandler(h, h2s)
+		}
+		g.servers = append(g.servers, &http.Server{
+			Addr:    cfg.HTTPAddr,
+			Handler: h,
+		})
+	}
+
+	if cfg.HTTPSAddr != "" {
+		handler := cfg.Handler
+		if cfg.HTTPSHandler != nil {
+			handler = cfg.HTTPSHandler
+		}
+		tlsConfig := cfg.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{
+				NextProtos: []string{"h2", "http/1.1"},
+			}
+		}
+		srv := &http.Server{
+			Addr:      cfg.HTTPSAddr,
+			Handler:   handler,
+			TLSConfig: tlsConfig,
+		}
+		if err := http2.ConfigureServer(srv, &http2.Server{}); err != nil {
+			return nil, fmt.Errorf("server: configure http2: %w", err)
+		}
+		g.servers = append(g.servers, srv)
+	}
+
+	return g, nil
+}
+
+// Run starts every configured listener and blocks until ctx is cancelled,
+// then gracefully shuts each one down within cfg.DrainTimeout. It returns the
+// first non-shutdown error encountered by any listener.
+func (g *Group) Run(ctx context.Context) error {
+	errs := make(chan error, len(g.servers))
+
+	for _, srv := range g.servers {
+		srv := srv
+		go func() {
+			var err error
+			if srv.TLSConfig != nil {
+				log.Printf("lastpoint: HTTP/2 server listening on %s", srv.Addr)
+				certFile, keyFile := g.cfg.CertFile, g.cfg.KeyFile
+				if srv.TLSConfig.GetCertificate != nil {
+					// A GetCertificate callback (e.g. ACME) supplies certs at
+					// handshake time; no cert/key files are needed.
+					certFile, keyFile = "", ""
+				}
+				err = srv.ListenAndServeTLS(certFile, keyFile)
+			} else {
+				log.Printf("lastpoint: HTTP/1.1 server listening on %s", srv.Addr)
+				err = srv.ListenAndServe()
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errs <- err
+				return
+			}
+			errs <- nil
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+	case err := <-errs:
+		if err != nil {
+			g.shutdown()
+			return err
+		}
+	}
+
+	return g.shutdown()
+}
+
+func (g *Group) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), g.cfg.DrainTimeout)
+	defer cancel()
+
+	var err error
+	for _, srv := range g.servers {
+		log.Printf("lastpoint: draining %s", srv.Addr)
+		if shutErr := srv.Shutdown(ctx); shutErr != nil && err == nil {
+			err = shutErr
+		}
+	}
+	return err
+}
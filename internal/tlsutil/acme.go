@@ -0,0 +1,47 @@
+package tlsutil
+
+import (
+	"context"
+	"crypto/tls"
+	"log"
+	"net/http"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// NewACMEManager returns an autocert.Manager that obtains and renews a
+// certificate for domains from Let's Encrypt (or another ACME CA), caching
+// issued certificates under cacheDir on disk.
+func NewACMEManager(cacheDir string, domains ...string) *autocert.Manager {
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Cache:      autocert.DirCache(cacheDir),
+	}
+}
+
+// ServeHTTPChallenge runs the HTTP-01 challenge responder for m on :80 until
+// ctx is cancelled. ACME requires this listener to be reachable on the
+// standard HTTP port, separate from lastpoint's own -http-addr.
+func ServeHTTPChallenge(ctx context.Context, m *autocert.Manager) {
+	srv := &http.Server{
+		Addr:    ":80",
+		Handler: m.HTTPHandler(nil),
+	}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Printf("tlsutil: ACME challenge listener: %v", err)
+	}
+}
+
+// TLSConfig returns a tls.Config wired to m.GetCertificate so the HTTPS
+// listener can serve ACME-issued certificates.
+func TLSConfig(m *autocert.Manager) *tls.Config {
+	return &tls.Config{
+		GetCertificate: m.GetCertificate,
+		NextProtos:     []string{"h2", "http/1.1", "acme-tls/1"},
+	}
+}
@@ -0,0 +1,87 @@
+// Package tlsutil provides the certificate-acquisition modes lastpoint
+// supports for its HTTPS listener: generating a self-signed cert on first
+// run, or obtaining one from an ACME CA.
+package tlsutil
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"time"
+)
+
+// EnsureSelfSigned makes sure certFile and keyFile exist, generating a
+// 2048-bit RSA key and a self-signed certificate valid for hosts (and
+// localhost/127.0.0.1) if they don't. It populates DNSNames/IPAddresses
+// rather than relying on the legacy Common Name field, so it works with
+// Go 1.15+ clients out of the box.
+func EnsureSelfSigned(certFile, keyFile string, hosts []string) error {
+	if fileExists(certFile) && fileExists(keyFile) {
+		return nil
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return fmt.Errorf("tlsutil: generate key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return fmt.Errorf("tlsutil: generate serial: %w", err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"lastpoint demo"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	dnsNames := append([]string{"localhost"}, hosts...)
+	ipAddresses := []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			ipAddresses = append(ipAddresses, ip)
+		}
+	}
+	template.DNSNames = dnsNames
+	template.IPAddresses = ipAddresses
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return fmt.Errorf("tlsutil: create certificate: %w", err)
+	}
+
+	if err := writePEM(certFile, "CERTIFICATE", der); err != nil {
+		return err
+	}
+	keyBytes := x509.MarshalPKCS1PrivateKey(key)
+	if err := writePEM(keyFile, "RSA PRIVATE KEY", keyBytes); err != nil {
+		return err
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func writePEM(path, blockType string, der []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("tlsutil: open %s: %w", path, err)
+	}
+	defer f.Close()
+	return pem.Encode(f, &pem.Block{Type: blockType, Bytes: der})
+}
@@ -0,0 +1,75 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnsureSelfSignedGeneratesValidSANs(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	if err := EnsureSelfSigned(certFile, keyFile, []string{"example.test"}); err != nil {
+		t.Fatalf("EnsureSelfSigned: %v", err)
+	}
+
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("LoadX509KeyPair: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		t.Fatalf("ParseCertificate: %v", err)
+	}
+
+	if err := cert.VerifyHostname("localhost"); err != nil {
+		t.Errorf("VerifyHostname(localhost): %v", err)
+	}
+	if err := cert.VerifyHostname("example.test"); err != nil {
+		t.Errorf("VerifyHostname(example.test): %v", err)
+	}
+	if cert.Subject.CommonName != "" {
+		t.Errorf("expected no legacy CommonName, got %q", cert.Subject.CommonName)
+	}
+
+	found := false
+	for _, ip := range cert.IPAddresses {
+		if ip.Equal(net.ParseIP("127.0.0.1")) {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected 127.0.0.1 in IPAddresses")
+	}
+}
+
+func TestEnsureSelfSignedLeavesExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	if err := EnsureSelfSigned(certFile, keyFile, nil); err != nil {
+		t.Fatalf("first EnsureSelfSigned: %v", err)
+	}
+	first, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("LoadX509KeyPair: %v", err)
+	}
+
+	if err := EnsureSelfSigned(certFile, keyFile, nil); err != nil {
+		t.Fatalf("second EnsureSelfSigned: %v", err)
+	}
+	second, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("LoadX509KeyPair: %v", err)
+	}
+
+	if string(first.Certificate[0]) != string(second.Certificate[0]) {
+		t.Error("expected EnsureSelfSigned to leave an existing cert untouched")
+	}
+}